@@ -87,3 +87,13 @@ func (cam *camera) VideoRecord(property prop.Media) (video.Reader, error) {
 func (cam *camera) Properties() []prop.Media {
 	return cam.session.Properties()
 }
+
+// camera intentionally does not implement driver.Controllable on macOS:
+// doing so for real would mean adding AVCaptureDevice-backed
+// lockForConfiguration/setExposureMode-style methods to
+// avfoundation.Session itself, and that package isn't touched anywhere in
+// this tree, so there's no session-level hook to bridge to. Per
+// driver.Controllable's own contract ("drivers that don't support any
+// control simply don't implement it"), callers already type-assert for it
+// rather than assuming every Driver has it; V4L2 implements it via direct
+// ioctls in camera_linux_controls.go, AVFoundation doesn't yet.
@@ -6,6 +6,7 @@ import "C"
 import (
 	"context"
 	"errors"
+	"fmt"
 	"image"
 	"io"
 	"log"
@@ -25,6 +26,22 @@ import (
 const (
 	maxEmptyFrameCount = 5
 	prioritizedDevice  = "video0"
+	// frameBufferRingSize is how many V4L2 buffers are queued at once, and
+	// how many private Go-owned buffers frames get copied into in
+	// rotation. More than one is required so a frame can be held onto by
+	// consumers (decode, an MJPEG sink, ...) while the driver is already
+	// filling the next one.
+	//
+	// Note this is not zero-copy: blackjack/webcam's ReadFrame hands back a
+	// slice aliasing its mmap region and reclaims it on its own DQBUF/QBUF
+	// schedule, with no hook to defer that reclamation until our refcount
+	// drops to zero. So every frame is still copied into a ring slot below
+	// before being wrapped in a driver.FrameBuffer; the ring/refcount only
+	// buys multiple consumers holding a frame at once, not avoiding the
+	// copy. A real zero-copy path would need a V4L2 binding that exposes
+	// manual QBUF control (e.g. go4vl) so release() could requeue the
+	// buffer itself instead of webcam doing it unconditionally.
+	frameBufferRingSize = 4
 )
 
 var (
@@ -72,6 +89,10 @@ type camera struct {
 	started         bool
 	mutex           sync.Mutex
 	cancel          func()
+	// bufs tracks frame buffers currently on loan to consumers, so Close
+	// can wait for them to be released instead of yanking the mmap region
+	// out from under whoever's still reading it.
+	bufs sync.WaitGroup
 }
 
 func init() {
@@ -121,6 +142,8 @@ func newCamera(path string) *camera {
 		webcam.PixelFormat(C.V4L2_PIX_FMT_UYVY):   frame.FormatUYVY,
 		webcam.PixelFormat(C.V4L2_PIX_FMT_MJPEG):  frame.FormatMJPEG,
 		webcam.PixelFormat(C.V4L2_PIX_FMT_Z16):    frame.FormatZ16,
+		webcam.PixelFormat(C.V4L2_PIX_FMT_H264):   frame.FormatH264,
+		webcam.PixelFormat(C.V4L2_PIX_FMT_HEVC):   frame.FormatH265,
 	}
 
 	reversedFormats := make(map[frame.Format]webcam.PixelFormat)
@@ -160,8 +183,7 @@ func (c *camera) Open() error {
 	}
 	c.log("Camera open")
 
-	// Late frames should be discarded. Buffering should be handled in higher level.
-	cam.SetBufferCount(1)
+	cam.SetBufferCount(frameBufferRingSize)
 	c.cam = cam
 	return nil
 }
@@ -180,9 +202,12 @@ func (c *camera) Close() error {
 		c.mutex.Lock()
 		defer c.mutex.Unlock()
 
-		// Note: StopStreaming frees frame buffers even if they are still used in Go code.
-		//       There is currently no convenient way to do this safely.
-		//       So, consumer of this stream must close camera after unusing all images.
+		// Wait for every outstanding FrameBuffer reference (e.g. an MJPEG
+		// sink still encoding the last frame while the encoder also holds
+		// it) to be released before reclaiming the V4L2 buffers, so
+		// StopStreaming can no longer free memory still in use in Go code.
+		c.log("Waiting for outstanding frame buffers to be released")
+		c.bufs.Wait()
 		c.cam.StopStreaming()
 		c.cancel = nil
 	}
@@ -193,10 +218,32 @@ func (c *camera) Close() error {
 
 func (c *camera) VideoRecord(p prop.Media) (video.Reader, error) {
 	c.log("Create new recorder for media: %v", p)
-	decoder, err := frame.NewDecoder(p.FrameFormat)
-	if err != nil {
-		c.log("Error while creating decoder: %s", err)
-		return nil, err
+	var decoder frame.Decoder
+	var err error
+	switch p.FrameFormat {
+	case frame.FormatZ16:
+		// Z16 decodes to a frame.DepthImage, not a color image.Image, so
+		// it's not in the regular format-keyed decoder registry; go
+		// straight to its dedicated constructor instead.
+		decoder = frame.NewZ16Decoder()
+	default:
+		decoder, err = frame.NewDecoder(p.FrameFormat)
+		if err != nil {
+			// H.264/H.265 have no built-in image.Image decoder; fall back to
+			// the optional ffmpeg-backed one (built with the "ffmpeg" tag)
+			// rather than failing outright. Most callers capturing a
+			// compressed format want VideoRecordEncoded instead, which skips
+			// this decode step entirely.
+			if frame.FFmpegDecoderFactory == nil {
+				c.log("Error while creating decoder: %s", err)
+				return nil, err
+			}
+			decoder, err = frame.FFmpegDecoderFactory(p.FrameFormat)
+			if err != nil {
+				c.log("Error while creating decoder: %s", err)
+				return nil, err
+			}
+		}
 	}
 
 	pf := c.reversedFormats[p.FrameFormat]
@@ -208,6 +255,16 @@ func (c *camera) VideoRecord(p prop.Media) (video.Reader, error) {
 	}
 	c.log("Actual image format: %s, %dx%d", actualPF, actualWidth, actualHeight)
 
+	// The driver is free to round the requested resolution to whatever it
+	// actually supports, so decode at its resolution and scale to what was
+	// asked for instead of silently handing back a garbled image.
+	var scaler frame.Scaler
+	if int(actualWidth) != p.Width || int(actualHeight) != p.Height {
+		c.log("Driver resolution (%dx%d) differs from requested (%dx%d), scaling every frame",
+			actualWidth, actualHeight, p.Width, p.Height)
+		scaler = frame.NewScalerFor(p.ScalerBackend)
+	}
+
 	if p.FrameRate > 0 {
 		err = c.cam.SetFramerate(float32(p.FrameRate))
 		c.log("Frame Rate: %d", p.FrameRate)
@@ -229,19 +286,32 @@ func (c *camera) VideoRecord(p prop.Media) (video.Reader, error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancel = cancel
-	var buf []byte
 	nbFrames := 0
 	framesSince := time.Now()
-	r := video.ReaderFunc(func() (img image.Image, release func(), err error) {
+	// ring holds frameBufferRingSize Go-owned buffers that frames are
+	// copied into in rotation. webcam.ReadFrame hands back a slice that
+	// aliases the V4L2 mmap region and gets reused by the driver on its
+	// own schedule (not once every FrameBuffer reference is released), so
+	// consumers can only safely hold a frame past the next ReadFrame call
+	// if it was copied out of that region first. Rotating through
+	// frameBufferRingSize private buffers, instead of a single reused one,
+	// still lets that many frames be in flight at once (e.g. an MJPEG sink
+	// and a WebRTC encoder each holding the current and previous frame).
+	ring := make([][]byte, frameBufferRingSize)
+	ringIdx := 0
+	// readRaw blocks for the next non-empty frame and hands back a
+	// FrameBuffer over it, without decoding. It's shared by the decoding
+	// video.Reader below and, for FormatMJPEG, by ReadJPEG, which skips the
+	// decode entirely since the raw bytes already are a JPEG image.
+	readRaw := func() (fb *driver.FrameBuffer, err error) {
 		// Lock to avoid accessing the buffer after StopStreaming()
 		c.mutex.Lock()
 		defer c.mutex.Unlock()
 
-		// Wait until a frame is ready
 		for i := 0; i < maxEmptyFrameCount; i++ {
 			if ctx.Err() != nil {
 				// Return EOF if the camera is already closed.
-				return nil, func() {}, io.EOF
+				return nil, io.EOF
 			}
 
 			err := cam.WaitForFrame(readTimeoutSec)
@@ -249,18 +319,18 @@ func (c *camera) VideoRecord(p prop.Media) (video.Reader, error) {
 			case nil:
 			case *webcam.Timeout:
 				c.log("Read timeout, take loo long to receive a frame from camera")
-				return nil, func() {}, errReadTimeout
+				return nil, errReadTimeout
 			default:
 				c.log("Error while waiting frame: %s", err)
 				// Camera has been stopped.
-				return nil, func() {}, err
+				return nil, err
 			}
 
 			b, err := cam.ReadFrame()
 			if err != nil {
 				c.log("Error while reading frame: %s", err)
 				// Camera has been stopped.
-				return nil, func() {}, err
+				return nil, err
 			}
 
 			// Frame is empty.
@@ -270,16 +340,6 @@ func (c *camera) VideoRecord(p prop.Media) (video.Reader, error) {
 				continue
 			}
 
-			if len(b) > len(buf) {
-				// Grow the intermediate buffer
-				buf = make([]byte, len(b))
-			}
-
-			// move the memory from mmap to Go. This will guarantee that any data that's going out
-			// from this reader will be Go safe. Otherwise, it's possible that outside of this reader
-			// that this memory is still being used even after we close it.
-			n := copy(buf, b)
-
 			nbFrames++
 			if nbFrames == 30*60*2 {
 				elapsedTime := time.Now().Sub(framesSince)
@@ -288,14 +348,168 @@ func (c *camera) VideoRecord(p prop.Media) (video.Reader, error) {
 				framesSince = time.Now()
 			}
 
-			img, release, err = decoder.Decode(buf[:n], p.Width, p.Height)
+			// Copy out of the mmap region into this frame's ring slot: b
+			// is only valid until the driver reclaims it, which can happen
+			// as soon as the next ReadFrame call. fb then tracks how many
+			// consumers (the decode below, plus whoever Refs it further
+			// downstream) are still using that slot, so Close can wait for
+			// all of them before tearing anything down.
+			slot := ring[ringIdx]
+			if len(b) > len(slot) {
+				slot = make([]byte, len(b))
+				ring[ringIdx] = slot
+			}
+			n := copy(slot, b)
+			ringIdx = (ringIdx + 1) % frameBufferRingSize
+
+			c.bufs.Add(1)
+			return driver.NewFrameBuffer(slot[:n], c.bufs.Done), nil
+		}
+		c.log("Too much consecutive empty undecodable frames")
+		return nil, errEmptyFrame
+	}
+
+	r := video.ReaderFunc(func() (img image.Image, release func(), err error) {
+		for {
+			fb, err := readRaw()
+			if err != nil {
+				return nil, func() {}, err
+			}
+
+			img, decRelease, err := decoder.Decode(fb.Bytes, int(actualWidth), int(actualHeight))
 			if errors.Is(err, frame.DecoderError) {
+				fb.Release()
 				continue
 			}
-			return img, release, err
+			if err != nil {
+				fb.Release()
+				return nil, func() {}, err
+			}
+
+			release = func() {
+				if decRelease != nil {
+					decRelease()
+				}
+				fb.Release()
+			}
+			if scaler != nil {
+				img = scaler.Scale(img, p.Width, p.Height, p.ResizeMode)
+			}
+			return img, release, nil
 		}
-		c.log("Too much consecutive empty undecodable frames")
-		return nil, func() {}, errEmptyFrame
+	})
+
+	// A driver capturing FormatMJPEG already produces JPEG bytes; expose
+	// them straight through video.mjpegserver.JPEGSource so it can skip the
+	// decode/re-encode round trip, but only when nothing else in this
+	// VideoRecord call needs the decoded image (no resize in play).
+	if p.FrameFormat == frame.FormatMJPEG && scaler == nil {
+		return &mjpegReader{Reader: r, readJPEG: func() ([]byte, func(), error) {
+			fb, err := readRaw()
+			if err != nil {
+				return nil, func() {}, err
+			}
+			return fb.Bytes, fb.Release, nil
+		}}, nil
+	}
+
+	return r, nil
+}
+
+// mjpegReader adds mjpegserver.JPEGSource to a video.Reader that captures
+// FormatMJPEG, so the raw JPEG bytes V4L2 handed back can be streamed out
+// without being decoded and re-encoded first.
+type mjpegReader struct {
+	video.Reader
+	readJPEG func() ([]byte, func(), error)
+}
+
+func (m *mjpegReader) ReadJPEG() ([]byte, func(), error) {
+	return m.readJPEG()
+}
+
+// VideoRecordEncoded is the passthrough counterpart of VideoRecord for
+// cameras that expose a compressed bitstream directly (FormatH264,
+// FormatH265). It skips decoding entirely: frames read from V4L2 are split
+// into NAL units and handed to the caller as-is, so they can be fed to a
+// WebRTC encoder without a decode/re-encode round trip.
+func (c *camera) VideoRecordEncoded(p prop.Media) (video.EncodedReader, error) {
+	if p.FrameFormat != frame.FormatH264 && p.FrameFormat != frame.FormatH265 {
+		return nil, fmt.Errorf("camera: %s is not a supported encoded format", p.FrameFormat)
+	}
+
+	pf := c.reversedFormats[p.FrameFormat]
+	c.log("Set FrameFormat and size: %s, %dx%d", p.FrameFormat, p.Width, p.Height)
+	actualPF, actualWidth, actualHeight, err := c.cam.SetImageFormat(pf, uint32(p.Width), uint32(p.Height))
+	if err != nil {
+		c.log("Error while setting image format: %s", err)
+		return nil, err
+	}
+	c.log("Actual image format: %s, %dx%d", actualPF, actualWidth, actualHeight)
+
+	if p.FrameRate > 0 {
+		if err := c.cam.SetFramerate(float32(p.FrameRate)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.cam.StartStreaming(); err != nil {
+		c.log("Error while starting streaming: %s", err)
+		return nil, err
+	}
+	c.log("Started streaming")
+
+	cam := c.cam
+	readTimeoutSec := getCameraReadTimeout()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	isKeyframe := frame.IsH264Keyframe
+	if p.FrameFormat == frame.FormatH265 {
+		isKeyframe = frame.IsH265Keyframe
+	}
+
+	var buf []byte
+	r := video.EncodedReaderFunc(func() (frame.AccessUnit, func(), error) {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		for i := 0; i < maxEmptyFrameCount; i++ {
+			if ctx.Err() != nil {
+				return frame.AccessUnit{}, func() {}, io.EOF
+			}
+
+			if err := cam.WaitForFrame(readTimeoutSec); err != nil {
+				switch err.(type) {
+				case *webcam.Timeout:
+					c.log("Read timeout, take loo long to receive a frame from camera")
+					return frame.AccessUnit{}, func() {}, errReadTimeout
+				default:
+					c.log("Error while waiting frame: %s", err)
+					return frame.AccessUnit{}, func() {}, err
+				}
+			}
+
+			b, err := cam.ReadFrame()
+			if err != nil {
+				c.log("Error while reading frame: %s", err)
+				return frame.AccessUnit{}, func() {}, err
+			}
+			if len(b) == 0 {
+				continue
+			}
+
+			if len(b) > len(buf) {
+				buf = make([]byte, len(b))
+			}
+			n := copy(buf, b)
+
+			nalus := frame.SplitAnnexB(buf[:n])
+			if len(nalus) == 0 {
+				continue
+			}
+			return frame.AccessUnit{NALUs: nalus, Keyframe: isKeyframe(nalus)}, func() {}, nil
+		}
+		return frame.AccessUnit{}, func() {}, errEmptyFrame
 	})
 
 	return r, nil
@@ -313,9 +527,10 @@ func (c *camera) Properties() []prop.Media {
 			if frameSize.StepWidth == 0 || frameSize.StepHeight == 0 {
 				properties = append(properties, prop.Media{
 					Video: prop.Video{
-						Width:       int(frameSize.MaxWidth),
-						Height:      int(frameSize.MaxHeight),
-						FrameFormat: supportedFormat,
+						Width:                 int(frameSize.MaxWidth),
+						Height:                int(frameSize.MaxHeight),
+						FrameFormat:           supportedFormat,
+						DepthUnitsMillimeters: depthUnitsMillimeters(supportedFormat),
 					},
 				})
 			} else {
@@ -338,9 +553,10 @@ func (c *camera) Properties() []prop.Media {
 
 					properties = append(properties, prop.Media{
 						Video: prop.Video{
-							Width:       width,
-							Height:      height,
-							FrameFormat: supportedFormat,
+							Width:                 width,
+							Height:                height,
+							FrameFormat:           supportedFormat,
+							DepthUnitsMillimeters: depthUnitsMillimeters(supportedFormat),
 						},
 					})
 				}
@@ -349,3 +565,14 @@ func (c *camera) Properties() []prop.Media {
 	}
 	return properties
 }
+
+// depthUnitsMillimeters reports the scale of a single Z16 sample: V4L2's
+// Z16 format is already one millimeter per unit, so depth consumers can
+// use the raw DepthImage values directly without per-device calibration.
+// Non-depth formats don't carry a meaningful value.
+func depthUnitsMillimeters(format frame.Format) float32 {
+	if format == frame.FormatZ16 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package camera
+
+// #include <linux/videodev2.h>
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/pion/mediadevices/pkg/driver"
+)
+
+// v4l2ControlIDs maps the well-known, cross-platform driver.ControlID
+// names to their V4L2_CID_* constant.
+var v4l2ControlIDs = map[driver.ControlID]uint32{
+	driver.ControlBrightness:              uint32(C.V4L2_CID_BRIGHTNESS),
+	driver.ControlContrast:                uint32(C.V4L2_CID_CONTRAST),
+	driver.ControlSaturation:              uint32(C.V4L2_CID_SATURATION),
+	driver.ControlExposureAuto:            uint32(C.V4L2_CID_EXPOSURE_AUTO),
+	driver.ControlExposureAbsolute:        uint32(C.V4L2_CID_EXPOSURE_ABSOLUTE),
+	driver.ControlFocusAbsolute:           uint32(C.V4L2_CID_FOCUS_ABSOLUTE),
+	driver.ControlWhiteBalanceTemperature: uint32(C.V4L2_CID_WHITE_BALANCE_TEMPERATURE),
+	driver.ControlPowerLineFrequency:      uint32(C.V4L2_CID_POWER_LINE_FREQUENCY),
+}
+
+// v4l2QueryCtrl mirrors the fields of struct v4l2_queryctrl that
+// VIDIOC_QUERYCTRL reads/writes.
+type v4l2QueryCtrl struct {
+	id           uint32
+	controlType  uint32
+	name         [32]byte
+	minimum      int32
+	maximum      int32
+	step         int32
+	defaultValue int32
+	flags        uint32
+	reserved     [2]uint32
+}
+
+// v4l2Control mirrors struct v4l2_control, used by VIDIOC_G_CTRL/S_CTRL.
+type v4l2Control struct {
+	id    uint32
+	value int32
+}
+
+func ioctl(fd uintptr, req uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Controls implements driver.Controllable by running VIDIOC_QUERYCTRL
+// against every well-known control, skipping the ones this device doesn't
+// support rather than failing the whole enumeration.
+func (c *camera) Controls() ([]driver.ControlInfo, error) {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var infos []driver.ControlInfo
+	for id, v4l2ID := range v4l2ControlIDs {
+		q := v4l2QueryCtrl{id: v4l2ID}
+		if err := ioctl(f.Fd(), uintptr(C.VIDIOC_QUERYCTRL), unsafe.Pointer(&q)); err != nil {
+			continue
+		}
+		infos = append(infos, driver.ControlInfo{
+			ID:      id,
+			Min:     q.minimum,
+			Max:     q.maximum,
+			Step:    q.step,
+			Default: q.defaultValue,
+		})
+	}
+	return infos, nil
+}
+
+// GetControl implements driver.Controllable via VIDIOC_G_CTRL.
+func (c *camera) GetControl(id driver.ControlID) (int32, error) {
+	v4l2ID, ok := v4l2ControlIDs[id]
+	if !ok {
+		return 0, fmt.Errorf("camera: unknown control %s", id)
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	ctrl := v4l2Control{id: v4l2ID}
+	if err := ioctl(f.Fd(), uintptr(C.VIDIOC_G_CTRL), unsafe.Pointer(&ctrl)); err != nil {
+		return 0, fmt.Errorf("camera: failed to get control %s: %w", id, err)
+	}
+	return ctrl.value, nil
+}
+
+// SetControl implements driver.Controllable via VIDIOC_S_CTRL.
+func (c *camera) SetControl(id driver.ControlID, value int32) error {
+	v4l2ID, ok := v4l2ControlIDs[id]
+	if !ok {
+		return fmt.Errorf("camera: unknown control %s", id)
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctrl := v4l2Control{id: v4l2ID, value: value}
+	if err := ioctl(f.Fd(), uintptr(C.VIDIOC_S_CTRL), unsafe.Pointer(&ctrl)); err != nil {
+		return fmt.Errorf("camera: failed to set control %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package camera
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/pion/mediadevices/pkg/driver"
+)
+
+// wellKnownControlIDs mirrors driver's exported ControlID constants, since
+// there's no exported way to enumerate them from the driver package itself.
+var wellKnownControlIDs = []driver.ControlID{
+	driver.ControlBrightness,
+	driver.ControlContrast,
+	driver.ControlSaturation,
+	driver.ControlExposureAuto,
+	driver.ControlExposureAbsolute,
+	driver.ControlFocusAbsolute,
+	driver.ControlWhiteBalanceTemperature,
+	driver.ControlPowerLineFrequency,
+}
+
+func TestV4L2ControlIDsCoversEveryWellKnownControl(t *testing.T) {
+	for _, id := range wellKnownControlIDs {
+		if _, ok := v4l2ControlIDs[id]; !ok {
+			t.Errorf("v4l2ControlIDs is missing an entry for %s", id)
+		}
+	}
+}
+
+func TestV4L2ControlStructLayout(t *testing.T) {
+	// v4l2Control mirrors struct v4l2_control { __u32 id; __s32 value; }:
+	// 8 bytes, value immediately after id with no padding. ioctl writes
+	// straight into this struct's memory, so a reorder or added field that
+	// changes its size/offsets would silently corrupt VIDIOC_G_CTRL/S_CTRL
+	// calls without this failing.
+	var ctrl v4l2Control
+	if got := unsafe.Sizeof(ctrl); got != 8 {
+		t.Fatalf("unsafe.Sizeof(v4l2Control{}) = %d, want 8", got)
+	}
+	if got := unsafe.Offsetof(ctrl.id); got != 0 {
+		t.Errorf("offsetof(id) = %d, want 0", got)
+	}
+	if got := unsafe.Offsetof(ctrl.value); got != 4 {
+		t.Errorf("offsetof(value) = %d, want 4", got)
+	}
+}
+
+func TestGetSetControlRejectUnknownID(t *testing.T) {
+	c := &camera{}
+	if _, err := c.GetControl("NotARealControl"); err == nil {
+		t.Error("expected GetControl to reject an unknown control id")
+	}
+	if err := c.SetControl("NotARealControl", 0); err == nil {
+		t.Error("expected SetControl to reject an unknown control id")
+	}
+}
@@ -0,0 +1,102 @@
+package rtsp
+
+import (
+	"fmt"
+
+	"github.com/aler9/gortsplib"
+	"github.com/aler9/gortsplib/pkg/rtph264"
+	"github.com/aler9/gortsplib/pkg/rtph265"
+	"github.com/pion/mediadevices/pkg/frame"
+	"github.com/pion/mediadevices/pkg/prop"
+)
+
+// negotiateVideoTrack picks the first H.264 or H.265 track out of the SDP
+// description and derives the prop.Media constraint-matching would need
+// (resolution, framerate) from its media attributes, falling back to zero
+// values when the SDP doesn't carry them.
+func negotiateVideoTrack(tracks gortsplib.Tracks) (*gortsplib.Track, prop.Media, error) {
+	for _, track := range tracks {
+		switch {
+		case track.IsH264():
+			width, height, fps := resolutionFromSDP(track)
+			return track, prop.Media{
+				Video: prop.Video{
+					Width:       width,
+					Height:      height,
+					FrameRate:   fps,
+					FrameFormat: frame.FormatH264,
+				},
+			}, nil
+		case track.IsH265():
+			width, height, fps := resolutionFromSDP(track)
+			return track, prop.Media{
+				Video: prop.Video{
+					Width:       width,
+					Height:      height,
+					FrameRate:   fps,
+					FrameFormat: frame.FormatH265,
+				},
+			}, nil
+		}
+	}
+	return nil, prop.Media{}, fmt.Errorf("rtsp: no H.264/H.265 video track in SDP")
+}
+
+// resolutionFromSDP reads the optional "x-dimensions"/"framerate" attributes
+// some RTSP servers advertise. Most don't, in which case constraint
+// matching simply won't be able to discriminate on resolution for this
+// device.
+func resolutionFromSDP(track *gortsplib.Track) (width, height int, fps float32) {
+	for _, attr := range track.Media.Attributes {
+		switch attr.Key {
+		case "x-dimensions":
+			fmt.Sscanf(attr.Value, "%d,%d", &width, &height)
+		case "framerate":
+			fmt.Sscanf(attr.Value, "%f", &fps)
+		}
+	}
+	return width, height, fps
+}
+
+// annexBStartCode is prepended to every NALU gortsplib's RTP decoders hand
+// back: RFC 6184/7798 depacketization strips the Annex B start code before
+// returning NAL units, but frame.IsH264Keyframe/IsH265Keyframe and the
+// downstream decoder (flatten in rtsp.go) both expect one, the same as any
+// other Annex B bitstream.
+var annexBStartCode = []byte{0, 0, 0, 1}
+
+// depacketize reassembles one RTP payload into Annex-B-prefixed NAL units,
+// reporting whether the resulting access unit is a keyframe. Exactly one of
+// h264Dec/h265Dec must be non-nil, matching whichever codec was negotiated
+// for the track; it must be the same decoder instance across calls for a
+// given session; FU-A fragments spanning multiple RTP packets (the norm
+// once a slice doesn't fit in one packet) only reassemble correctly if
+// the decoder's internal fragmentation state carries over between calls.
+func depacketize(h264Dec *rtph264.Decoder, h265Dec *rtph265.Decoder, payload []byte) (nalus [][]byte, keyframe bool, err error) {
+	switch {
+	case h264Dec != nil:
+		nalus, _, err = h264Dec.Decode(payload)
+		if err != nil {
+			return nil, false, err
+		}
+		nalus = withAnnexBStartCodes(nalus)
+		return nalus, frame.IsH264Keyframe(nalus), nil
+	case h265Dec != nil:
+		nalus, _, err = h265Dec.Decode(payload)
+		if err != nil {
+			return nil, false, err
+		}
+		nalus = withAnnexBStartCodes(nalus)
+		return nalus, frame.IsH265Keyframe(nalus), nil
+	default:
+		return nil, false, fmt.Errorf("rtsp: unsupported track codec")
+	}
+}
+
+// withAnnexBStartCodes prepends annexBStartCode to each NALU in place.
+func withAnnexBStartCodes(nalus [][]byte) [][]byte {
+	for i, nalu := range nalus {
+		nalus[i] = append(append([]byte{}, annexBStartCode...), nalu...)
+	}
+	return nalus
+}
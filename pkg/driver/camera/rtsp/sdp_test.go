@@ -0,0 +1,32 @@
+package rtsp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDepacketizeRequiresADecoder(t *testing.T) {
+	// Neither codec negotiated: depacketize must fail rather than silently
+	// drop the packet, so a caller misconfiguring the session notices.
+	if _, _, err := depacketize(nil, nil, []byte{0x01, 0x02}); err == nil {
+		t.Error("expected an error when neither h264Dec nor h265Dec is set")
+	}
+}
+
+func TestWithAnnexBStartCodes(t *testing.T) {
+	nalus := [][]byte{{0x67, 0xAA}, {0x68, 0xBB, 0xCC}}
+	got := withAnnexBStartCodes(nalus)
+
+	want := [][]byte{
+		{0, 0, 0, 1, 0x67, 0xAA},
+		{0, 0, 0, 1, 0x68, 0xBB, 0xCC},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d NALUs, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("nalus[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
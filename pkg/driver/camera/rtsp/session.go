@@ -0,0 +1,117 @@
+package rtsp
+
+import (
+	"fmt"
+
+	"github.com/aler9/gortsplib"
+	"github.com/aler9/gortsplib/pkg/base"
+	"github.com/aler9/gortsplib/pkg/rtph264"
+	"github.com/aler9/gortsplib/pkg/rtph265"
+	"github.com/pion/mediadevices/pkg/frame"
+	"github.com/pion/mediadevices/pkg/io/video"
+	"github.com/pion/mediadevices/pkg/prop"
+)
+
+// session isolates the gortsplib-specific wiring (connecting, SDP
+// negotiation, RTP depacketization) behind the small surface the camera
+// type needs, the same way avfoundation.Session does for the macOS driver.
+type session struct {
+	client *gortsplib.Client
+	track  *gortsplib.Track
+	props  prop.Media
+
+	aus chan frame.AccessUnit
+
+	// h264Dec/h265Dec carry FU-A fragmentation-reassembly state across RTP
+	// packets, so exactly one of them (matching the negotiated codec) is
+	// created once per session and reused for every onPacketRTP call, not
+	// recreated per packet.
+	h264Dec *rtph264.Decoder
+	h265Dec *rtph265.Decoder
+}
+
+func dial(url string) (*session, error) {
+	u, err := base.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RTSP url: %w", err)
+	}
+
+	client := &gortsplib.Client{}
+	if err := client.Start(u.Scheme, u.Host); err != nil {
+		return nil, err
+	}
+
+	tracks, baseURL, _, err := client.Describe(u)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	track, props, err := negotiateVideoTrack(tracks)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	if err := client.SetupAndPlay(gortsplib.Tracks{track}, baseURL); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	s := &session{
+		client: client,
+		track:  track,
+		props:  props,
+		aus:    make(chan frame.AccessUnit, 4),
+	}
+	switch {
+	case track.IsH264():
+		s.h264Dec = rtph264.NewDecoder()
+	case track.IsH265():
+		s.h265Dec = rtph265.NewDecoder()
+	}
+	client.OnPacketRTP = s.onPacketRTP
+	return s, nil
+}
+
+func (s *session) close() {
+	s.client.Close()
+}
+
+func (s *session) videoRecordEncoded() (video.EncodedReader, error) {
+	return video.EncodedReaderFunc(func() (frame.AccessUnit, func(), error) {
+		au, ok := <-s.aus
+		if !ok {
+			return frame.AccessUnit{}, func() {}, fmt.Errorf("rtsp: stream closed")
+		}
+		return au, func() {}, nil
+	}), nil
+}
+
+// onPacketRTP depacketizes an incoming RTP packet for the negotiated video
+// track and, once a full access unit has been reassembled, pushes it to any
+// VideoRecordEncoded reader. If the channel is already full, the stale
+// access unit sitting in it is dropped to make room, matching the
+// drop-oldest backpressure used elsewhere in the capture pipeline (see
+// mjpegserver.broadcast) — a slow consumer catches up to the live stream
+// instead of replaying old video.
+func (s *session) onPacketRTP(trackID int, payload []byte) {
+	nalus, keyframe, err := depacketize(s.h264Dec, s.h265Dec, payload)
+	if err != nil {
+		return
+	}
+
+	au := frame.AccessUnit{NALUs: nalus, Keyframe: keyframe}
+	select {
+	case s.aus <- au:
+	default:
+		select {
+		case <-s.aus:
+		default:
+		}
+		select {
+		case s.aus <- au:
+		default:
+		}
+	}
+}
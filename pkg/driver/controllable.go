@@ -0,0 +1,42 @@
+package driver
+
+// ControlID identifies a runtime-adjustable camera control. Well-known
+// controls use stable names so cross-platform code can address them
+// without caring whether the underlying driver is V4L2 or AVFoundation.
+type ControlID string
+
+const (
+	ControlBrightness              ControlID = "Brightness"
+	ControlContrast                ControlID = "Contrast"
+	ControlSaturation              ControlID = "Saturation"
+	ControlExposureAuto            ControlID = "ExposureAuto"
+	ControlExposureAbsolute        ControlID = "ExposureAbsolute"
+	ControlFocusAbsolute           ControlID = "FocusAbsolute"
+	ControlWhiteBalanceTemperature ControlID = "WhiteBalanceTemperature"
+	ControlPowerLineFrequency      ControlID = "PowerLineFrequency"
+)
+
+// ControlInfo describes one control's valid range and default, as reported
+// by the driver.
+type ControlInfo struct {
+	ID      ControlID
+	Min     int32
+	Max     int32
+	Step    int32
+	Default int32
+}
+
+// Controllable is an optional interface a Driver can implement to expose
+// runtime-adjustable controls (brightness, exposure, focus, white
+// balance, ...). Drivers that don't support any control simply don't
+// implement it; callers should type-assert for it rather than relying on
+// every Driver to have it.
+type Controllable interface {
+	// Controls enumerates the controls this device currently supports.
+	Controls() ([]ControlInfo, error)
+	// GetControl returns the current value of id.
+	GetControl(id ControlID) (int32, error)
+	// SetControl sets id to value, which should fall within the range
+	// reported by Controls.
+	SetControl(id ControlID, value int32) error
+}
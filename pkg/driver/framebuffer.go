@@ -0,0 +1,41 @@
+package driver
+
+import "sync/atomic"
+
+// FrameBuffer is a single frame handed out to multiple consumers (e.g. an
+// MJPEG sink and a WebRTC encoder reading the same frame) with a refcount
+// instead of each consumer needing its own copy. Consumers must call
+// Release exactly once when they're done reading Bytes; release is only
+// invoked once every consumer that acquired a reference has released it.
+//
+// Bytes must be backed by memory the driver owns for as long as a
+// reference is outstanding (e.g. one of a rotating pool of buffers), not
+// by memory a lower layer (like a V4L2 mmap region) can reclaim on its own
+// schedule — FrameBuffer has no way to defer that reclamation itself.
+type FrameBuffer struct {
+	Bytes []byte
+
+	refs    int32
+	release func()
+}
+
+// NewFrameBuffer wraps bytes so they can be shared, refcounted, across
+// multiple consumers. release is called once the last reference is
+// released.
+func NewFrameBuffer(bytes []byte, release func()) *FrameBuffer {
+	return &FrameBuffer{Bytes: bytes, refs: 1, release: release}
+}
+
+// Ref adds a reference to fb. Every call to Ref must be paired with a call
+// to Release.
+func (fb *FrameBuffer) Ref() {
+	atomic.AddInt32(&fb.refs, 1)
+}
+
+// Release drops a reference to fb. Once the last reference is released,
+// the underlying buffer is handed back to the driver.
+func (fb *FrameBuffer) Release() {
+	if atomic.AddInt32(&fb.refs, -1) == 0 {
+		fb.release()
+	}
+}
@@ -0,0 +1,109 @@
+package frame
+
+// AccessUnit is a single encoded access unit (one decodable picture worth of
+// NAL units) pulled straight from a compressed-format camera, with no
+// decode step applied. It's the payload handed out by a passthrough
+// video.EncodedReader for FormatH264/FormatH265 sources.
+type AccessUnit struct {
+	// NALUs holds the raw NAL units that make up this access unit, in
+	// bitstream order, each still carrying its Annex B start code.
+	NALUs [][]byte
+	// Keyframe reports whether this access unit can be decoded on its own
+	// (IDR for H.264, IRAP for HEVC), so consumers can sync to the stream
+	// without parsing slice headers themselves.
+	Keyframe bool
+}
+
+// nalUnitType masks are defined in ITU-T H.264 7.3.1 / H.265 7.3.1.2.
+const (
+	h264NALTypeIDR  = 5
+	h265NALTypeBase = 16 // NAL types 16-23 are IRAP pictures (BLA/IDR/CRA)
+	h265NALTypeMax  = 23
+)
+
+// SplitAnnexB splits an Annex B encoded bitstream (0x000001 or 0x00000001
+// start codes) into individual NAL units, start code included, in the order
+// they appear.
+func SplitAnnexB(buf []byte) [][]byte {
+	starts := make([]int, 0, 4)
+	for i := 0; i+2 < len(buf); i++ {
+		if buf[i] == 0 && buf[i+1] == 0 && buf[i+2] == 1 {
+			start := i
+			if i > 0 && buf[i-1] == 0 {
+				// 4-byte start code: keep its leading zero with this NALU.
+				start = i - 1
+			}
+			starts = append(starts, start)
+			i += 2
+		}
+	}
+	if len(starts) == 0 {
+		return nil
+	}
+
+	nalus := make([][]byte, 0, len(starts))
+	for i, start := range starts {
+		end := len(buf)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		nalus = append(nalus, buf[start:end])
+	}
+	return nalus
+}
+
+// IsH264Keyframe reports whether any NAL unit in nalus is an IDR slice.
+func IsH264Keyframe(nalus [][]byte) bool {
+	for _, nalu := range nalus {
+		hdr := h264NALHeader(nalu)
+		if hdr < 0 {
+			continue
+		}
+		if hdr&0x1f == h264NALTypeIDR {
+			return true
+		}
+	}
+	return false
+}
+
+// IsH265Keyframe reports whether any NAL unit in nalus is an IRAP picture.
+func IsH265Keyframe(nalus [][]byte) bool {
+	for _, nalu := range nalus {
+		hdr := h265NALHeader(nalu)
+		if hdr < 0 {
+			continue
+		}
+		naluType := (hdr >> 1) & 0x3f
+		if naluType >= h265NALTypeBase && naluType <= h265NALTypeMax {
+			return true
+		}
+	}
+	return false
+}
+
+func h264NALHeader(nalu []byte) int {
+	i := annexBPrefixLen(nalu)
+	if i < 0 || i >= len(nalu) {
+		return -1
+	}
+	return int(nalu[i])
+}
+
+func h265NALHeader(nalu []byte) int {
+	i := annexBPrefixLen(nalu)
+	if i < 0 || i >= len(nalu) {
+		return -1
+	}
+	return int(nalu[i])
+}
+
+func annexBPrefixLen(nalu []byte) int {
+	switch {
+	case len(nalu) >= 4 && nalu[0] == 0 && nalu[1] == 0 && nalu[2] == 0 && nalu[3] == 1:
+		return 4
+	case len(nalu) >= 3 && nalu[0] == 0 && nalu[1] == 0 && nalu[2] == 1:
+		return 3
+	default:
+		return -1
+	}
+}
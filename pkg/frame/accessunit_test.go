@@ -0,0 +1,51 @@
+package frame
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitAnnexB(t *testing.T) {
+	buf := []byte{0, 0, 0, 1, 0x67, 0xAA, 0, 0, 1, 0x68, 0xBB, 0xCC}
+	nalus := SplitAnnexB(buf)
+	if len(nalus) != 2 {
+		t.Fatalf("got %d NAL units, want 2", len(nalus))
+	}
+	if !bytes.Equal(nalus[0], []byte{0, 0, 0, 1, 0x67, 0xAA}) {
+		t.Errorf("nalus[0] = %v", nalus[0])
+	}
+	if !bytes.Equal(nalus[1], []byte{0, 0, 1, 0x68, 0xBB, 0xCC}) {
+		t.Errorf("nalus[1] = %v", nalus[1])
+	}
+}
+
+func TestSplitAnnexBNoStartCode(t *testing.T) {
+	if nalus := SplitAnnexB([]byte{1, 2, 3}); nalus != nil {
+		t.Errorf("got %v, want nil", nalus)
+	}
+}
+
+func TestIsH264Keyframe(t *testing.T) {
+	idr := [][]byte{{0, 0, 0, 1, 0x65, 0xAA}} // type 5 (IDR), nal_ref_idc 3
+	if !IsH264Keyframe(idr) {
+		t.Error("expected IDR NAL unit to be detected as keyframe")
+	}
+
+	nonIDR := [][]byte{{0, 0, 0, 1, 0x61, 0xAA}} // type 1 (non-IDR slice)
+	if IsH264Keyframe(nonIDR) {
+		t.Error("expected non-IDR NAL unit not to be a keyframe")
+	}
+}
+
+func TestIsH265Keyframe(t *testing.T) {
+	// NAL type 19 (IDR_W_RADL) sits at bits 1-6 of the first header byte.
+	idr := [][]byte{{0, 0, 0, 1, 19 << 1, 0x01}}
+	if !IsH265Keyframe(idr) {
+		t.Error("expected IRAP NAL unit to be detected as keyframe")
+	}
+
+	trail := [][]byte{{0, 0, 0, 1, 1 << 1, 0x01}} // NAL type 1 (TRAIL_R)
+	if IsH265Keyframe(trail) {
+		t.Error("expected non-IRAP NAL unit not to be a keyframe")
+	}
+}
@@ -0,0 +1,145 @@
+package frame
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// bilinearScaler is the default Scaler: a bilinear resampler implemented
+// directly against image.Image.At in plain float64, trading sharpness and
+// the couple-x speedup a real fixed-point/separable pass would give for
+// simplicity and not depending on golang.org/x/image/draw (see
+// scaler_xdraw.go for that tradeoff going the other way).
+type bilinearScaler struct{}
+
+func (bilinearScaler) Scale(src image.Image, width, height int, mode ResizeMode) image.Image {
+	if width <= 0 || height <= 0 {
+		return src
+	}
+
+	switch mode {
+	case ResizeModeCrop:
+		return resampleRect(src, cropRect(src.Bounds(), width, height), width, height)
+	case ResizeModeLetterbox:
+		return letterbox(src, width, height)
+	default:
+		return resampleRect(src, src.Bounds(), width, height)
+	}
+}
+
+// cropRect returns the largest centered rectangle of srcBounds whose aspect
+// ratio matches width:height, so resampling it to width x height fills the
+// target with no padding.
+func cropRect(srcBounds image.Rectangle, width, height int) image.Rectangle {
+	srcW, srcH := srcBounds.Dx(), srcBounds.Dy()
+	targetRatio := float64(width) / float64(height)
+	srcRatio := float64(srcW) / float64(srcH)
+
+	if srcRatio > targetRatio {
+		// Source is wider than target: crop the sides.
+		cropW := int(float64(srcH) * targetRatio)
+		offset := (srcW - cropW) / 2
+		return image.Rect(srcBounds.Min.X+offset, srcBounds.Min.Y, srcBounds.Min.X+offset+cropW, srcBounds.Max.Y)
+	}
+	// Source is taller than target: crop top/bottom.
+	cropH := int(float64(srcW) / targetRatio)
+	offset := (srcH - cropH) / 2
+	return image.Rect(srcBounds.Min.X, srcBounds.Min.Y+offset, srcBounds.Max.X, srcBounds.Min.Y+offset+cropH)
+}
+
+// letterbox resamples src to fit within width x height while preserving
+// its aspect ratio, centering it over a black canvas of that size.
+func letterbox(src image.Image, width, height int) image.Image {
+	srcBounds := src.Bounds()
+	srcRatio := float64(srcBounds.Dx()) / float64(srcBounds.Dy())
+	targetRatio := float64(width) / float64(height)
+
+	innerW, innerH := width, height
+	if srcRatio > targetRatio {
+		innerH = int(float64(width) / srcRatio)
+	} else {
+		innerW = int(float64(height) * srcRatio)
+	}
+
+	inner := resampleRect(src, srcBounds, innerW, innerH)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	offsetX := (width - innerW) / 2
+	offsetY := (height - innerH) / 2
+	for y := 0; y < innerH; y++ {
+		for x := 0; x < innerW; x++ {
+			dst.Set(offsetX+x, offsetY+y, inner.At(x, y))
+		}
+	}
+	return dst
+}
+
+// resampleRect resizes the srcRect portion of src to width x height,
+// bilinearly sampling each destination pixel directly in floating point
+// (not a separable two-pass filter, and not fixed-point).
+func resampleRect(src image.Image, srcRect image.Rectangle, width, height int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	srcW, srcH := srcRect.Dx(), srcRect.Dy()
+	if srcW <= 0 || srcH <= 0 || width <= 0 || height <= 0 {
+		return dst
+	}
+
+	xRatio := float64(srcW) / float64(width)
+	yRatio := float64(srcH) / float64(height)
+
+	for y := 0; y < height; y++ {
+		sy := float64(srcRect.Min.Y) + (float64(y)+0.5)*yRatio - 0.5
+		for x := 0; x < width; x++ {
+			sx := float64(srcRect.Min.X) + (float64(x)+0.5)*xRatio - 0.5
+			dst.Set(x, y, bilinearSample(src, srcRect, sx, sy))
+		}
+	}
+	return dst
+}
+
+func bilinearSample(src image.Image, bounds image.Rectangle, sx, sy float64) color.Color {
+	clamp := func(v, lo, hi int) int {
+		if v < lo {
+			return lo
+		}
+		if v > hi {
+			return hi
+		}
+		return v
+	}
+
+	x0 := int(math.Floor(sx))
+	y0 := int(math.Floor(sy))
+	fx := sx - float64(x0)
+	fy := sy - float64(y0)
+
+	x0 = clamp(x0, bounds.Min.X, bounds.Max.X-1)
+	x1 := clamp(x0+1, bounds.Min.X, bounds.Max.X-1)
+	y0 = clamp(y0, bounds.Min.Y, bounds.Max.Y-1)
+	y1 := clamp(y0+1, bounds.Min.Y, bounds.Max.Y-1)
+
+	c00 := src.At(x0, y0)
+	c10 := src.At(x1, y0)
+	c01 := src.At(x0, y1)
+	c11 := src.At(x1, y1)
+
+	top := lerpColor(c00, c10, fx)
+	bottom := lerpColor(c01, c11, fx)
+	return lerpColor(top, bottom, fy)
+}
+
+func lerpColor(a, b color.Color, t float64) color.Color {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	lerp := func(x, y uint32) uint16 {
+		return uint16(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA64{
+		R: lerp(ar, br),
+		G: lerp(ag, bg),
+		B: lerp(ab, bb),
+		A: lerp(aa, ba),
+	}
+}
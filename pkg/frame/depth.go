@@ -0,0 +1,148 @@
+package frame
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math"
+)
+
+// DepthImage is the decoded form of a Z16 frame: one image.Gray16 sample
+// per pixel, holding the raw distance in millimeters (see
+// prop.Video.DepthUnitsMillimeters) rather than a color value, so the
+// sensor's native precision survives the capture pipeline instead of being
+// squashed into 8-bit color.
+type DepthImage struct {
+	*image.Gray16
+}
+
+// NewZ16Decoder returns a Decoder for FormatZ16. NewDecoder doesn't know
+// this format (V4L2 capture is the only producer of it so far, and it
+// needs a DepthImage rather than a color image.Image out the other end),
+// so camera.VideoRecord selects it explicitly instead of going through the
+// regular format-keyed decoder lookup.
+func NewZ16Decoder() Decoder {
+	return decoderFunc(decodeZ16)
+}
+
+// decodeZ16 decodes a Z16 frame (one little-endian uint16 per pixel) into
+// a DepthImage. There's no lossy color step here, unlike the other
+// decoders: the millimeter values are kept as-is.
+func decodeZ16(buf []byte, width, height int) (image.Image, func(), error) {
+	img := image.NewGray16(image.Rect(0, 0, width, height))
+
+	n := width * height
+	if max := len(buf) / 2; n > max {
+		n = max
+	}
+	for i := 0; i < n; i++ {
+		mm := binary.LittleEndian.Uint16(buf[i*2:])
+		img.Pix[i*2] = byte(mm >> 8)
+		img.Pix[i*2+1] = byte(mm)
+	}
+
+	return DepthImage{img}, func() {}, nil
+}
+
+// Palette selects the color ramp ColorizeDepth maps depth values onto.
+type Palette int
+
+const (
+	// PaletteJet reproduces OpenCV's COLORMAP_JET: blue (near) through
+	// green and yellow to red (far).
+	PaletteJet Palette = iota
+	// PaletteViridis is perceptually uniform and colorblind-friendly,
+	// trading the intuitive "red is far/hot" association for accuracy.
+	PaletteViridis
+)
+
+// ColorizeDepth renders img's raw millimeter samples as an image.RGBA
+// preview, linearly mapping [min, max] millimeters onto palette. A sample
+// of 0 (the sensor's "no return" value) is always rendered black,
+// regardless of where 0 falls relative to min/max.
+func ColorizeDepth(img *DepthImage, min, max uint16, palette Palette) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(bounds)
+
+	span := float64(max) - float64(min)
+	if span <= 0 {
+		span = 1
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			mm := img.Gray16At(x, y).Y
+			if mm == 0 {
+				dst.Set(x, y, color.Black)
+				continue
+			}
+
+			clamped := mm
+			if clamped < min {
+				clamped = min
+			} else if clamped > max {
+				clamped = max
+			}
+			t := (float64(clamped) - float64(min)) / span
+
+			var c color.RGBA
+			switch palette {
+			case PaletteViridis:
+				c = viridis(t)
+			default:
+				c = jet(t)
+			}
+			dst.Set(x, y, c)
+		}
+	}
+	return dst
+}
+
+func jet(t float64) color.RGBA {
+	return color.RGBA{
+		R: channel(1.5 - math.Abs(4*t-3)),
+		G: channel(1.5 - math.Abs(4*t-2)),
+		B: channel(1.5 - math.Abs(4*t-1)),
+		A: 255,
+	}
+}
+
+// viridisStops is a coarse sample of matplotlib's viridis colormap;
+// intermediate values are linearly interpolated between the nearest two.
+var viridisStops = [][3]float64{
+	{0.267, 0.005, 0.329},
+	{0.283, 0.141, 0.458},
+	{0.254, 0.265, 0.530},
+	{0.207, 0.372, 0.553},
+	{0.164, 0.471, 0.558},
+	{0.128, 0.567, 0.551},
+	{0.135, 0.659, 0.518},
+	{0.267, 0.749, 0.441},
+	{0.478, 0.821, 0.318},
+	{0.741, 0.873, 0.150},
+	{0.993, 0.906, 0.144},
+}
+
+func viridis(t float64) color.RGBA {
+	pos := t * float64(len(viridisStops)-1)
+	i := int(pos)
+	if i >= len(viridisStops)-1 {
+		i = len(viridisStops) - 2
+	}
+	frac := pos - float64(i)
+
+	a, b := viridisStops[i], viridisStops[i+1]
+	r := a[0] + (b[0]-a[0])*frac
+	g := a[1] + (b[1]-a[1])*frac
+	bch := a[2] + (b[2]-a[2])*frac
+	return color.RGBA{R: channel(r), G: channel(g), B: channel(bch), A: 255}
+}
+
+func channel(v float64) uint8 {
+	if v < 0 {
+		v = 0
+	} else if v > 1 {
+		v = 1
+	}
+	return uint8(v * 255)
+}
@@ -0,0 +1,76 @@
+package frame
+
+import (
+	"encoding/binary"
+	"image/color"
+	"testing"
+)
+
+func TestDecodeZ16(t *testing.T) {
+	buf := make([]byte, 2*2*2)
+	binary.LittleEndian.PutUint16(buf[0:], 1000)
+	binary.LittleEndian.PutUint16(buf[2:], 2000)
+	binary.LittleEndian.PutUint16(buf[4:], 3000)
+	binary.LittleEndian.PutUint16(buf[6:], 4000)
+
+	img, release, err := decodeZ16(buf, 2, 2)
+	if err != nil {
+		t.Fatalf("decodeZ16: %v", err)
+	}
+	release()
+
+	depth, ok := img.(DepthImage)
+	if !ok {
+		t.Fatalf("got %T, want DepthImage", img)
+	}
+	if got := depth.Gray16At(0, 0).Y; got != 1000 {
+		t.Errorf("pixel(0,0) = %d, want 1000", got)
+	}
+	if got := depth.Gray16At(1, 1).Y; got != 4000 {
+		t.Errorf("pixel(1,1) = %d, want 4000", got)
+	}
+}
+
+func TestNewZ16DecoderReturnsDepthImage(t *testing.T) {
+	img, release, err := NewZ16Decoder().Decode(make([]byte, 8), 2, 2)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	defer release()
+	if _, ok := img.(DepthImage); !ok {
+		t.Fatalf("got %T, want DepthImage", img)
+	}
+}
+
+func TestColorizeDepthZeroIsBlack(t *testing.T) {
+	buf := make([]byte, 2)
+	binary.LittleEndian.PutUint16(buf, 0)
+	img, release, err := decodeZ16(buf, 1, 1)
+	if err != nil {
+		t.Fatalf("decodeZ16: %v", err)
+	}
+	defer release()
+
+	depth := img.(DepthImage)
+	rgba := ColorizeDepth(&depth, 0, 5000, PaletteJet)
+	if c := rgba.At(0, 0); c != (color.RGBA{A: 255}) {
+		t.Errorf("expected a 0mm sample to render black, got %v", c)
+	}
+}
+
+func TestColorizeDepthClampsOutOfRange(t *testing.T) {
+	buf := make([]byte, 2)
+	binary.LittleEndian.PutUint16(buf, 9000)
+	img, release, err := decodeZ16(buf, 1, 1)
+	if err != nil {
+		t.Fatalf("decodeZ16: %v", err)
+	}
+	defer release()
+
+	depth := img.(DepthImage)
+	far := ColorizeDepth(&depth, 0, 1000, PaletteJet)
+	clamped := ColorizeDepth(&depth, 0, 9000, PaletteJet)
+	if far.At(0, 0) != clamped.At(0, 0) {
+		t.Errorf("expected an out-of-range sample to clamp to the same color as max, got %v vs %v", far.At(0, 0), clamped.At(0, 0))
+	}
+}
@@ -0,0 +1,152 @@
+//go:build ffmpeg
+
+package frame
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"os/exec"
+	"sync"
+)
+
+func init() {
+	FFmpegDecoderFactory = NewFFmpegDecoder
+}
+
+var ffmpegCodecByFormat = map[Format]string{
+	FormatH264: "h264",
+	FormatH265: "hevc",
+}
+
+// NewFFmpegDecoder returns a Decoder that decodes a compressed format
+// (FormatH264, FormatH265) by feeding the bitstream to a single, long-lived
+// ffmpeg process and reading raw frames back. A persistent process (as
+// opposed to one ffmpeg invocation per access unit) is required: SPS/PPS
+// and inter-frame (P/B) references only decode correctly if ffmpeg keeps
+// its own decoder state across calls, the same way any other stateful
+// streaming decoder would.
+func NewFFmpegDecoder(format Format) (Decoder, error) {
+	codec, ok := ffmpegCodecByFormat[format]
+	if !ok {
+		return nil, fmt.Errorf("frame: no ffmpeg codec known for format %s", format)
+	}
+
+	cmd := exec.Command("ffmpeg",
+		"-loglevel", "error",
+		"-f", codec,
+		"-i", "pipe:0",
+		"-f", "rawvideo",
+		"-pix_fmt", "rgba",
+		"pipe:1",
+	)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("frame: failed to start ffmpeg: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("frame: failed to start ffmpeg: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("frame: failed to start ffmpeg: %w", err)
+	}
+
+	d := &ffmpegDecoder{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: stdout,
+		in:     make(chan []byte, 64),
+		out:    make(chan image.Image, 2),
+		errc:   make(chan error, 1),
+	}
+	go d.writeLoop()
+	return decoderFunc(d.decode), nil
+}
+
+// ffmpegDecoder keeps the ffmpeg process and pipes alive across Decode
+// calls so its internal decoder state (SPS/PPS, reference frames) carries
+// over between access units. Feeding stdin and draining stdout happen on
+// their own goroutines rather than inside decode: ffmpeg commonly needs
+// more than one access unit queued up before its first output frame
+// appears (B-frame reordering, SPS/PPS-only access units, multi-NALU
+// pictures), so a synchronous write-then-blocking-read per decode call
+// would wedge the caller's read loop waiting for output that depends on
+// input only that same loop can supply.
+type ffmpegDecoder struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.Reader
+
+	in   chan []byte
+	out  chan image.Image
+	errc chan error
+
+	startRead sync.Once
+
+	// width/height/stride are set once, from the first decode call (see
+	// decode/readLoop), and assumed constant afterwards: camera.VideoRecord
+	// opens a fresh decoder whenever the requested resolution changes.
+	width, height, stride int
+}
+
+func (d *ffmpegDecoder) writeLoop() {
+	for buf := range d.in {
+		if _, err := d.stdin.Write(buf); err != nil {
+			select {
+			case d.errc <- fmt.Errorf("frame: ffmpeg stdin write failed: %w", err):
+			default:
+			}
+			return
+		}
+	}
+}
+
+func (d *ffmpegDecoder) readLoop() {
+	r := bufio.NewReader(d.stdout)
+	for {
+		raw := make([]byte, d.frameSize())
+		if _, err := io.ReadFull(r, raw); err != nil {
+			select {
+			case d.errc <- fmt.Errorf("frame: ffmpeg stdout read failed: %w", err):
+			default:
+			}
+			return
+		}
+		img := &image.RGBA{Pix: raw, Stride: d.stride, Rect: image.Rect(0, 0, d.width, d.height)}
+		d.out <- img
+	}
+}
+
+func (d *ffmpegDecoder) frameSize() int {
+	return d.width * d.height * 4
+}
+
+func (d *ffmpegDecoder) decode(buf []byte, width, height int) (image.Image, func(), error) {
+	d.startRead.Do(func() {
+		d.width, d.height, d.stride = width, height, width*4
+		go d.readLoop()
+	})
+
+	select {
+	case d.in <- buf:
+	default:
+		// The writer goroutine can't keep up; drop this access unit rather
+		// than block the caller's read loop indefinitely.
+	}
+
+	select {
+	case err := <-d.errc:
+		return nil, func() {}, fmt.Errorf("frame: ffmpeg decode failed: %w", err)
+	case img := <-d.out:
+		return img, func() {}, nil
+	default:
+		// ffmpeg hasn't produced a frame for an earlier access unit yet;
+		// the caller's DecoderError handling treats this the same as any
+		// other transient decode miss and simply retries on its next
+		// frame, by which point the background readLoop may have caught
+		// up.
+		return nil, func() {}, fmt.Errorf("frame: no decoded frame ready yet")
+	}
+}
@@ -0,0 +1,10 @@
+package frame
+
+// FFmpegDecoderFactory, when non-nil, constructs a Decoder for a
+// compressed format (FormatH264, FormatH265) by shelling out to ffmpeg.
+// It's set by ffmpeg_decoder.go, which is gated behind the "ffmpeg" build
+// tag so the default build doesn't require an ffmpeg binary on PATH.
+// Callers that want image.Image out of a compressed-only camera (see
+// camera.VideoRecord, rtsp.camera.VideoRecord) should fall back to
+// it when NewDecoder doesn't know the format.
+var FFmpegDecoderFactory func(Format) (Decoder, error)
@@ -0,0 +1,12 @@
+package frame
+
+// FormatH264 and FormatH265 identify cameras that deliver an already
+// compressed bitstream (e.g. V4L2_PIX_FMT_H264/HEVC) instead of raw pixel
+// data. Unlike the other Format values, there is no Decoder registered for
+// them by default: consumers are expected to take the encoded access units
+// straight from the driver (see AccessUnit) and hand them to a WebRTC
+// encoder without a decode/re-encode round-trip.
+const (
+	FormatH264 Format = "H264"
+	FormatH265 Format = "H265"
+)
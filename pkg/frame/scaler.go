@@ -0,0 +1,70 @@
+package frame
+
+import "image"
+
+// ResizeMode controls how a Scaler reconciles a decoded frame's actual
+// size with the width/height requested in prop.Video when a camera
+// couldn't deliver the exact resolution asked for; prop.Video.ResizeMode
+// selects which mode applies.
+type ResizeMode int
+
+const (
+	// ResizeModeStretch scales width and height independently to exactly
+	// match the target size, distorting the aspect ratio if needed. This
+	// is the default, matching the previous (unscaled) behavior as
+	// closely as possible.
+	ResizeModeStretch ResizeMode = iota
+	// ResizeModeCrop scales to fill the target size, preserving aspect
+	// ratio, and crops whatever overhangs.
+	ResizeModeCrop
+	// ResizeModeLetterbox scales to fit within the target size, preserving
+	// aspect ratio, and pads the remainder with black.
+	ResizeModeLetterbox
+)
+
+// Scaler resizes and/or crops a decoded frame to a target resolution. It's
+// what lets camera.VideoRecord decode at the resolution the driver actually
+// delivered and still hand callers a frame at the resolution they asked
+// for in prop.Video, instead of silently producing garbled output when the
+// two differ.
+type Scaler interface {
+	Scale(src image.Image, width, height int, mode ResizeMode) image.Image
+}
+
+// NewScaler returns the default Scaler: a bilinear resampler implemented
+// directly against image.Image, trading a bit of sharpness for not
+// depending on golang.org/x/image/draw. This matters on the
+// CPU-constrained boards (Raspberry Pi and similar) mediadevices commonly
+// targets for camera capture.
+func NewScaler() Scaler {
+	return bilinearScaler{}
+}
+
+// ScalerBackend selects which Scaler implementation NewScalerFor returns,
+// letting a caller trade quality/CPU cost through prop.Video.ScalerBackend
+// instead of always getting the built-in bilinearScaler.
+type ScalerBackend int
+
+const (
+	// ScalerBackendBilinear is the built-in resampler returned by
+	// NewScaler. Default (zero value); always available.
+	ScalerBackendBilinear ScalerBackend = iota
+	// ScalerBackendXDraw defers to golang.org/x/image/draw's bilinear
+	// interpolator (see scaler_xdraw.go's NewDrawScaler), built behind the
+	// "xdraw" tag so the default build doesn't pull in that dependency.
+	ScalerBackendXDraw
+)
+
+// DrawScalerFactory, when non-nil, constructs the xdraw-backed Scaler.
+// It's set by scaler_xdraw.go's init, gated behind the "xdraw" build tag.
+var DrawScalerFactory func() Scaler
+
+// NewScalerFor returns the Scaler backend requested by
+// prop.Video.ScalerBackend, falling back to NewScaler if the caller asked
+// for a backend that wasn't compiled in.
+func NewScalerFor(backend ScalerBackend) Scaler {
+	if backend == ScalerBackendXDraw && DrawScalerFactory != nil {
+		return DrawScalerFactory()
+	}
+	return NewScaler()
+}
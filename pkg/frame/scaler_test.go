@@ -0,0 +1,86 @@
+package frame
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidImage(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestScalerStretch(t *testing.T) {
+	src := solidImage(4, 2, color.RGBA{R: 200, A: 255})
+	dst := NewScaler().Scale(src, 8, 8, ResizeModeStretch)
+	if b := dst.Bounds(); b.Dx() != 8 || b.Dy() != 8 {
+		t.Fatalf("got %v, want 8x8", b)
+	}
+}
+
+func TestScalerCropPreservesAspectRatio(t *testing.T) {
+	// 8x4 source cropped to a 1:1 target should sample only the center 4x4.
+	src := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			if x < 2 || x >= 6 {
+				src.Set(x, y, color.RGBA{R: 255, A: 255}) // edges, should be cropped out
+			} else {
+				src.Set(x, y, color.RGBA{G: 255, A: 255}) // center, should survive
+			}
+		}
+	}
+
+	dst := NewScaler().Scale(src, 4, 4, ResizeModeCrop)
+	r, g, _, _ := dst.At(2, 2).RGBA()
+	if r != 0 || g == 0 {
+		t.Errorf("expected center pixel to come from the cropped (green) region, got r=%d g=%d", r, g)
+	}
+}
+
+func TestScalerLetterboxPadsWithBlack(t *testing.T) {
+	src := solidImage(4, 4, color.RGBA{R: 255, A: 255})
+	dst := NewScaler().Scale(src, 8, 4, ResizeModeLetterbox)
+	if b := dst.Bounds(); b.Dx() != 8 || b.Dy() != 4 {
+		t.Fatalf("got %v, want 8x4", b)
+	}
+
+	// A square source letterboxed into a wider target should pad the
+	// left/right columns with black rather than stretching the image.
+	r, _, _, a := dst.At(0, 2).RGBA()
+	if r != 0 || a != 0 {
+		t.Errorf("expected left padding pixel to be transparent black, got r=%d a=%d", r, a)
+	}
+}
+
+func TestScalerNoOpOnInvalidSize(t *testing.T) {
+	src := solidImage(2, 2, color.RGBA{R: 1, A: 255})
+	dst := NewScaler().Scale(src, 0, 0, ResizeModeStretch)
+	if dst != image.Image(src) {
+		t.Error("expected non-positive target size to return src unchanged")
+	}
+}
+
+func TestNewScalerForFallsBackWithoutXDraw(t *testing.T) {
+	// This test runs without the "xdraw" build tag, so DrawScalerFactory
+	// is never registered; ScalerBackendXDraw must still hand back a
+	// working Scaler instead of nil.
+	if DrawScalerFactory != nil {
+		t.Fatal("DrawScalerFactory unexpectedly set without the xdraw build tag")
+	}
+	if s := NewScalerFor(ScalerBackendXDraw); s == nil {
+		t.Error("expected NewScalerFor to fall back to the default Scaler, got nil")
+	}
+}
+
+func TestNewScalerForBilinearIsDefault(t *testing.T) {
+	if _, ok := NewScalerFor(ScalerBackendBilinear).(bilinearScaler); !ok {
+		t.Error("expected ScalerBackendBilinear to return the built-in bilinearScaler")
+	}
+}
@@ -0,0 +1,55 @@
+//go:build xdraw
+
+package frame
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+func init() {
+	DrawScalerFactory = NewDrawScaler
+}
+
+// NewDrawScaler returns a Scaler backed by golang.org/x/image/draw's
+// bilinear interpolator, for callers that have already pulled in that
+// dependency and would rather reuse its (SIMD-friendlier) implementation
+// than the built-in bilinearScaler.
+func NewDrawScaler() Scaler {
+	return xdrawScaler{}
+}
+
+type xdrawScaler struct{}
+
+func (xdrawScaler) Scale(src image.Image, width, height int, mode ResizeMode) image.Image {
+	if width <= 0 || height <= 0 {
+		return src
+	}
+
+	srcRect := src.Bounds()
+	if mode == ResizeModeCrop {
+		srcRect = cropRect(srcRect, width, height)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if mode == ResizeModeLetterbox {
+		innerW, innerH := letterboxSize(srcRect, width, height)
+		offsetX, offsetY := (width-innerW)/2, (height-innerH)/2
+		dstRect := image.Rect(offsetX, offsetY, offsetX+innerW, offsetY+innerH)
+		draw.BiLinear.Scale(dst, dstRect, src, srcRect, draw.Src, nil)
+		return dst
+	}
+
+	draw.BiLinear.Scale(dst, dst.Bounds(), src, srcRect, draw.Src, nil)
+	return dst
+}
+
+func letterboxSize(srcBounds image.Rectangle, width, height int) (innerW, innerH int) {
+	srcRatio := float64(srcBounds.Dx()) / float64(srcBounds.Dy())
+	targetRatio := float64(width) / float64(height)
+	if srcRatio > targetRatio {
+		return width, int(float64(width) / srcRatio)
+	}
+	return int(float64(height) * srcRatio), height
+}
@@ -0,0 +1,21 @@
+package video
+
+import "github.com/pion/mediadevices/pkg/frame"
+
+// EncodedReader is the encoded counterpart of Reader: instead of decoded
+// image.Image frames, it yields access units straight from a driver that
+// already produces a compressed bitstream (e.g. V4L2 H.264/HEVC capture),
+// so a caller can feed a WebRTC encoder without a decode/re-encode round
+// trip.
+type EncodedReader interface {
+	// Read returns the next access unit. Like Reader.Read, the returned
+	// release function must be called once the caller is done with au.
+	Read() (au frame.AccessUnit, release func(), err error)
+}
+
+// EncodedReaderFunc is a proxy type for EncodedReader.
+type EncodedReaderFunc func() (frame.AccessUnit, func(), error)
+
+func (f EncodedReaderFunc) Read() (frame.AccessUnit, func(), error) {
+	return f()
+}
@@ -0,0 +1,215 @@
+// Package mjpegserver exposes a video.Reader as a multipart/x-mixed-replace
+// MJPEG stream over HTTP, plus a single-shot JPEG snapshot endpoint. It's a
+// lightweight way to look at a camera (in a browser or VLC) without pulling
+// in a full WebRTC stack.
+package mjpegserver
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"sync"
+
+	"github.com/pion/mediadevices/pkg/io/video"
+)
+
+const boundary = "mediadevicesframe"
+
+// JPEGSource is an optional interface a video.Reader can implement to hand
+// back already-encoded JPEG bytes for the current frame (e.g. a camera
+// opened with frame.FormatMJPEG). When the wrapped reader implements it,
+// Server skips the decode/re-encode round trip entirely.
+type JPEGSource interface {
+	ReadJPEG() (data []byte, release func(), err error)
+}
+
+// Server wraps a video.Reader and broadcasts it to any number of HTTP
+// clients. Each client gets its own backpressure buffer; a client that
+// can't keep up has its stale frame dropped rather than stalling capture
+// for everyone else.
+type Server struct {
+	reader  video.Reader
+	quality int
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+
+	snapshotMu sync.RWMutex
+	snapshot   []byte
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithJPEGQuality sets the JPEG quality (1-100) used when re-encoding
+// frames that aren't already MJPEG. Defaults to jpeg.DefaultQuality.
+func WithJPEGQuality(quality int) Option {
+	return func(s *Server) {
+		s.quality = quality
+	}
+}
+
+// New creates a Server around reader. Call Start to begin pulling frames.
+func New(reader video.Reader, opts ...Option) *Server {
+	s := &Server{
+		reader:  reader,
+		quality: jpeg.DefaultQuality,
+		clients: make(map[chan []byte]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start begins pulling frames from the wrapped reader in a background
+// goroutine and broadcasting them to connected clients. Start itself returns
+// immediately; the goroutine stops once the reader returns an error (e.g.
+// the camera was closed).
+func (s *Server) Start() {
+	go s.run()
+}
+
+func (s *Server) run() {
+	jpegSource, _ := s.reader.(JPEGSource)
+	for {
+		data, err := s.nextFrame(jpegSource)
+		if err != nil {
+			return
+		}
+
+		s.snapshotMu.Lock()
+		s.snapshot = data
+		s.snapshotMu.Unlock()
+
+		s.broadcast(data)
+	}
+}
+
+func (s *Server) nextFrame(jpegSource JPEGSource) ([]byte, error) {
+	if jpegSource != nil {
+		data, release, err := jpegSource.ReadJPEG()
+		if err != nil {
+			release()
+			return nil, err
+		}
+		// data aliases a buffer release() may hand back to the driver (e.g.
+		// a V4L2 ring slot); it's stored in s.snapshot and fanned out to
+		// clients well after this call returns, so it has to be copied out
+		// before release() runs.
+		out := make([]byte, len(data))
+		copy(out, data)
+		release()
+		return out, nil
+	}
+
+	img, release, err := s.reader.Read()
+	defer release()
+	if err != nil {
+		return nil, err
+	}
+	return s.encode(img)
+}
+
+func (s *Server) encode(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: s.quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// broadcast fans data out to every subscribed client, dropping whatever
+// stale frame is sitting in a slow client's buffer instead of blocking on
+// it.
+func (s *Server) broadcast(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.clients {
+		select {
+		case ch <- data:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- data
+		}
+	}
+}
+
+func (s *Server) subscribe() chan []byte {
+	ch := make(chan []byte, 1)
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan []byte) {
+	s.mu.Lock()
+	delete(s.clients, ch)
+	s.mu.Unlock()
+}
+
+// ServeHTTP serves a multipart/x-mixed-replace MJPEG stream, one boundary
+// part per captured frame, suitable for an <img> tag, a browser navigated
+// directly to it, or VLC's network stream.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", fmt.Sprintf("multipart/x-mixed-replace; boundary=%s", boundary))
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-ch:
+			fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", boundary, len(data))
+			if _, err := w.Write(data); err != nil {
+				return
+			}
+			fmt.Fprint(w, "\r\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// ServeSnapshot serves the most recently captured frame as a single
+// image/jpeg response. It's meant to be mounted at a path like
+// /snapshot.jpg.
+func (s *Server) ServeSnapshot(w http.ResponseWriter, r *http.Request) {
+	s.snapshotMu.RLock()
+	data := s.snapshot
+	s.snapshotMu.RUnlock()
+
+	if data == nil {
+		http.Error(w, "no frame captured yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	w.Write(data)
+}
+
+// Handler returns an http.Handler serving the MJPEG stream at "/" and the
+// latest snapshot at "/snapshot.jpg", ready to be mounted with
+// http.Handle/http.ListenAndServe.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.ServeHTTP)
+	mux.HandleFunc("/snapshot.jpg", s.ServeSnapshot)
+	return mux
+}
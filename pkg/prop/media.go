@@ -0,0 +1,38 @@
+// Package prop describes the properties a driver's track can produce or
+// that a caller wants to request (resolution, frame rate, pixel format,
+// ...), the same shape used for GetUserMedia-style constraint matching.
+package prop
+
+import "github.com/pion/mediadevices/pkg/frame"
+
+// Media describes a single track's properties. Video's fields are
+// promoted onto Media directly, since every driver in this tree is video
+// only.
+type Media struct {
+	Video
+}
+
+// Video describes a stream of frames coming out of a camera-like driver.
+type Video struct {
+	Width       int
+	Height      int
+	FrameRate   float32
+	FrameFormat frame.Format
+
+	// ResizeMode selects how frame.Scaler reconciles the driver's actual
+	// resolution with Width/Height when they don't match. Defaults to
+	// ResizeModeStretch (the zero value).
+	ResizeMode ResizeMode
+
+	// ScalerBackend selects which frame.Scaler implementation VideoRecord
+	// uses when ResizeMode applies, trading quality against CPU cost.
+	// Defaults to ScalerBackendBilinear (the zero value), which is always
+	// available; ScalerBackendXDraw requires building with the "xdraw" tag
+	// and silently falls back to the default otherwise.
+	ScalerBackend ScalerBackend
+
+	// DepthUnitsMillimeters is the scale factor a depth camera's raw
+	// samples (frame.DepthImage's Gray16 values) must be multiplied by to
+	// get millimeters. It's 0 for non-depth formats.
+	DepthUnitsMillimeters float32
+}
@@ -0,0 +1,16 @@
+package prop
+
+import "github.com/pion/mediadevices/pkg/frame"
+
+// ResizeMode is re-exported here so constraint/driver code can select a
+// frame.ResizeMode without importing the frame package directly. It's read
+// from Video.ResizeMode by camera.VideoRecord when the driver couldn't
+// deliver the exact width/height requested, to pick how the frame gets
+// reconciled to that size (see frame.Scaler).
+type ResizeMode = frame.ResizeMode
+
+const (
+	ResizeModeStretch   = frame.ResizeModeStretch
+	ResizeModeCrop      = frame.ResizeModeCrop
+	ResizeModeLetterbox = frame.ResizeModeLetterbox
+)
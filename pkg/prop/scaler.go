@@ -0,0 +1,14 @@
+package prop
+
+import "github.com/pion/mediadevices/pkg/frame"
+
+// ScalerBackend is re-exported here so constraint/driver code can select a
+// frame.ScalerBackend without importing the frame package directly. It's
+// read from Video.ScalerBackend by camera.VideoRecord to pick which
+// frame.Scaler implementation to resample with.
+type ScalerBackend = frame.ScalerBackend
+
+const (
+	ScalerBackendBilinear = frame.ScalerBackendBilinear
+	ScalerBackendXDraw    = frame.ScalerBackendXDraw
+)